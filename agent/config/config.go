@@ -17,11 +17,9 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"io"
 	"io/ioutil"
 	"os"
 	"reflect"
-	"strconv"
 	"strings"
 
 	"github.com/aws/amazon-ecs-agent/agent/ec2"
@@ -60,11 +58,18 @@ func (lhs *Config) Merge(rhs Config) *Config {
 	return lhs //make it chainable
 }
 
-// Complete returns true if all fields of the config are populated / nonzero
+// Complete returns true if all fields of the config are populated / nonzero,
+// except those tagged `complete:"skip"` -- fields with no environment
+// variable or default of their own, which would otherwise make Complete
+// unsatisfiable.
 func (cfg *Config) Complete() bool {
 	cfgElem := reflect.ValueOf(cfg).Elem()
+	cfgStructField := reflect.Indirect(reflect.ValueOf(cfg)).Type()
 
 	for i := 0; i < cfgElem.NumField(); i++ {
+		if cfgStructField.Field(i).Tag.Get("complete") == "skip" {
+			continue
+		}
 		if utils.ZeroOrNil(cfgElem.Field(i).Interface()) {
 			return false
 		}
@@ -106,6 +111,18 @@ func (cfg *Config) CheckMissingAndDepreciated() error {
 			log.Warn("Use of deprecated configuration key", "key", cfgStructField.Field(i).Name, "message", deprecatedTag)
 		}
 	}
+	if cfg.DockerEndpoint != "" {
+		if _, err := ParseDockerEndpoint(cfg.DockerEndpoint); err != nil {
+			log.Crit("Unsupported DockerEndpoint", "err", err)
+			fatalFields = append(fatalFields, "DockerEndpoint")
+		}
+	}
+
+	if err := cfg.ValidateLogDriver(cfg.EngineLogDriver); err != nil {
+		log.Crit("Invalid default logging driver", "err", err)
+		fatalFields = append(fatalFields, "EngineLogDriver")
+	}
+
 	if len(fatalFields) > 0 {
 		return errors.New("Missing required fields: " + strings.Join(fatalFields, ", "))
 	}
@@ -139,7 +156,7 @@ func (cfg *Config) TrimWhitespace() {
 
 func DefaultConfig() Config {
 	return Config{
-		DockerEndpoint:   "unix:///var/run/docker.sock",
+		DockerEndpoint:   defaultDockerEndpoint,
 		ReservedPorts:    []uint16{SSH_PORT, DOCKER_RESERVED_PORT, DOCKER_RESERVED_SSL_PORT, AGENT_INTROSPECTION_PORT},
 		ReservedPortsUDP: []uint16{},
 		DataDir:          "/data/",
@@ -179,95 +196,13 @@ func FileConfig() Config {
 	return config
 }
 
-// EnvironmentConfig reads the given configs from the environment and attempts
-// to convert them to the given type
+// EnvironmentConfig reads every field of Config that carries an `env` tag
+// from its environment variable; see loadFromEnv in env.go.
 func EnvironmentConfig() Config {
-	endpoint := os.Getenv("ECS_BACKEND_HOST")
-
-	clusterRef := os.Getenv("ECS_CLUSTER")
-	awsRegion := os.Getenv("AWS_DEFAULT_REGION")
-
-	dockerEndpoint := os.Getenv("DOCKER_HOST")
-	engineAuthType := os.Getenv("ECS_ENGINE_AUTH_TYPE")
-	engineAuthData := os.Getenv("ECS_ENGINE_AUTH_DATA")
-	// mgresko: adding variables to configure logging drivers
-	engineLogDriver := os.Getenv("DOCKER_LOG_DRIVER")
-	engineLogOpts := os.Getenv("DOCKER_LOG_OPTS")
-
-	var checkpoint bool
-	dataDir := os.Getenv("ECS_DATADIR")
-	if dataDir != "" {
-		// if we have a directory to checkpoint to, default it to be on
-		checkpoint = utils.ParseBool(os.Getenv("ECS_CHECKPOINT"), true)
-	} else {
-		// if the directory is not set, default to checkpointing off for
-		// backwards compatibility
-		checkpoint = utils.ParseBool(os.Getenv("ECS_CHECKPOINT"), false)
-	}
-
-	// Format: json array, e.g. [1,2,3]
-	reservedPortEnv := os.Getenv("ECS_RESERVED_PORTS")
-	portDecoder := json.NewDecoder(strings.NewReader(reservedPortEnv))
-	var reservedPorts []uint16
-	err := portDecoder.Decode(&reservedPorts)
-	// EOF means the string was blank as opposed to UnexepctedEof which means an
-	// invalid parse
-	// Blank is not a warning; we have sane defaults
-	if err != io.EOF && err != nil {
-		log.Warn("Invalid format for \"ECS_RESERVED_PORTS\" environment variable; expected a JSON array like [1,2,3].", "err", err)
-	}
+	cfg := Config{}
+	loadFromEnv(&cfg)
 
-	reservedPortUDPEnv := os.Getenv("ECS_RESERVED_PORTS_UDP")
-	portDecoderUDP := json.NewDecoder(strings.NewReader(reservedPortUDPEnv))
-	var reservedPortsUDP []uint16
-	err = portDecoderUDP.Decode(&reservedPortsUDP)
-	// EOF means the string was blank as opposed to UnexepctedEof which means an
-	// invalid parse
-	// Blank is not a warning; we have sane defaults
-	if err != io.EOF && err != nil {
-		log.Warn("Invalid format for \"ECS_RESERVED_PORTS_UDP\" environment variable; expected a JSON array like [1,2,3].", "err", err)
-	}
-
-	updateDownloadDir := os.Getenv("ECS_UPDATE_DOWNLOAD_DIR")
-	updatesEnabled := utils.ParseBool(os.Getenv("ECS_UPDATES_ENABLED"), false)
-
-	disableMetrics := utils.ParseBool(os.Getenv("ECS_DISABLE_METRICS"), false)
-	dockerGraphPath := os.Getenv("ECS_DOCKER_GRAPHPATH")
-
-	reservedMemoryEnv := os.Getenv("ECS_RESERVED_MEMORY")
-	var reservedMemory64 uint64
-	var reservedMemory uint16
-	if reservedMemoryEnv == "" {
-		reservedMemory = 0
-	} else {
-		reservedMemory64, err = strconv.ParseUint(reservedMemoryEnv, 10, 16)
-		if err != nil {
-			log.Warn("Invalid format for \"ECS_RESERVED_MEMORY\" environment variable; expected unsigned integer.", "err", err)
-			reservedMemory = 0
-		} else {
-			reservedMemory = uint16(reservedMemory64)
-		}
-	}
-
-	return Config{
-		Cluster:           clusterRef,
-		APIEndpoint:       endpoint,
-		AWSRegion:         awsRegion,
-		DockerEndpoint:    dockerEndpoint,
-		ReservedPorts:     reservedPorts,
-		ReservedPortsUDP:  reservedPortsUDP,
-		DataDir:           dataDir,
-		Checkpoint:        checkpoint,
-		EngineAuthType:    engineAuthType,
-		EngineAuthData:    []byte(engineAuthData),
-		UpdatesEnabled:    updatesEnabled,
-		UpdateDownloadDir: updateDownloadDir,
-		DisableMetrics:    disableMetrics,
-		DockerGraphPath:   dockerGraphPath,
-		ReservedMemory:    reservedMemory,
-		EngineLogDriver:   engineLogDriver,
-		EngineLogOpts:     engineLogOpts,
-	}
+	return cfg
 }
 
 var ec2MetadataClient = ec2.DefaultClient
@@ -300,6 +235,7 @@ func NewConfig() (config *Config, err error) {
 		return config, nil
 	}
 
+	config.Merge(DockerContextConfig())
 	config.Merge(FileConfig())
 
 	if config.AWSRegion == "" {
@@ -307,11 +243,29 @@ func NewConfig() (config *Config, err error) {
 		config.Merge(EC2MetadataConfig())
 	}
 
+	// EngineAuthRegistry must be defaulted after every source that could set it
+	// (env, Docker context, config file) has been merged in: Merge's
+	// zero-field-wins semantics mean a field that's already non-zero can
+	// never be overridden, so defaulting it any earlier -- e.g. inside
+	// EnvironmentConfig -- would permanently block a registry configured in
+	// the config file from ever taking effect.
+	config.EngineAuthRegistry = utils.DefaultIfBlank(config.EngineAuthRegistry, defaultEngineAuthRegistry)
+
+	if authData, authErr := resolveEngineAuthData(config.EngineAuthType, config.EngineAuthRegistry); authErr != nil {
+		log.Warn("Unable to resolve docker engine auth from credential helper", "err", authErr)
+	} else if authData != nil {
+		config.EngineAuthData = authData
+	}
+
 	return config, err
 }
 
 // String returns a lossy string representation of the config suitable for human readable display.
 // Consequently, it *should not* return any sensitive information.
 func (config *Config) String() string {
-	return fmt.Sprintf("Cluster: %v, Region: %v, DataDir: %v, Checkpoint: %v, AuthType: %v, UpdatesEnabled: %v, DisableMetrics: %v, ReservedMem: %v", config.Cluster, config.AWSRegion, config.DataDir, config.Checkpoint, config.EngineAuthType, config.UpdatesEnabled, config.DisableMetrics, config.ReservedMemory)
+	engineAuthData := "none"
+	if len(config.EngineAuthData) > 0 {
+		engineAuthData = "<redacted>"
+	}
+	return fmt.Sprintf("Cluster: %v, Region: %v, DataDir: %v, Checkpoint: %v, AuthType: %v, AuthData: %v, UpdatesEnabled: %v, DisableMetrics: %v, ReservedMem: %v, DockerContext: %v", config.Cluster, config.AWSRegion, config.DataDir, config.Checkpoint, config.EngineAuthType, engineAuthData, config.UpdatesEnabled, config.DisableMetrics, config.ReservedMemory, config.DockerContext)
 }
@@ -0,0 +1,82 @@
+// Copyright 2014-2015 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package config
+
+import "testing"
+
+func TestEnvironmentConfigDoesNotDefaultEngineAuthRegistry(t *testing.T) {
+	// The default must be applied once, in NewConfig, after every source
+	// that could set EngineAuthRegistry has been merged in -- not here --
+	// otherwise a registry set by the config file or Docker context could
+	// never win over the default via Merge's zero-field-wins semantics.
+	cfg := EnvironmentConfig()
+	if cfg.EngineAuthRegistry != "" {
+		t.Fatalf("EnvironmentConfig().EngineAuthRegistry = %q, want empty", cfg.EngineAuthRegistry)
+	}
+}
+
+func TestMergeLetsFileConfigRegistryWinOverDefault(t *testing.T) {
+	cfg := Config{}
+	cfg.Merge(Config{EngineAuthRegistry: "https://my-private-registry.example.com"})
+	cfg.EngineAuthRegistry = defaultIfBlankAuthRegistry(cfg.EngineAuthRegistry)
+
+	if cfg.EngineAuthRegistry != "https://my-private-registry.example.com" {
+		t.Fatalf("EngineAuthRegistry = %q, want the merged-in registry to survive defaulting", cfg.EngineAuthRegistry)
+	}
+}
+
+// defaultIfBlankAuthRegistry mirrors the defaulting NewConfig performs,
+// without requiring a full NewConfig() run (which touches the filesystem
+// and EC2 metadata).
+func defaultIfBlankAuthRegistry(registry string) string {
+	if registry != "" {
+		return registry
+	}
+	return defaultEngineAuthRegistry
+}
+
+func TestCompleteIgnoresDockerContextFields(t *testing.T) {
+	// DockerContext/DockerCAPath/DockerCertPath/DockerKeyPath/
+	// DockerSkipTLSVerify are all zero for a host that isn't using a named
+	// Docker context; they must not block Complete() from returning true
+	// once every other field is populated.
+	cfg := Config{
+		Cluster:          "default",
+		APIEndpoint:      "https://ecs.amazonaws.com",
+		AWSRegion:        "us-west-2",
+		DockerEndpoint:   "unix:///var/run/docker.sock",
+		ReservedPorts:    []uint16{22},
+		ReservedPortsUDP: []uint16{22},
+		DataDir:          "/data/",
+		Checkpoint:       true,
+		EngineAuthType:   "dockercfg-helper",
+		EngineAuthData:   []byte("x"),
+
+		EngineAuthRegistry: defaultEngineAuthRegistry,
+		UpdatesEnabled:     true,
+		UpdateDownloadDir:  "/tmp",
+		DisableMetrics:     true,
+		DockerGraphPath:    "/var/lib/docker",
+		ReservedMemory:     1,
+		EngineLogDriver:    "json-file",
+		EngineLogOpts:      "max-size=10m",
+
+		AvailableLoggingDrivers: []string{"json-file"},
+		ClusterArn:              "arn:aws:ecs:us-west-2:1:cluster/default",
+	}
+
+	if !cfg.Complete() {
+		t.Fatal("Complete() = false, want true: complete:\"skip\" fields should not gate it")
+	}
+}
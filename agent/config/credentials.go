@@ -0,0 +1,231 @@
+// Copyright 2014-2015 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package config
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// EngineAuthTypeDockercfgHelper resolves credentials by invoking the
+	// docker-credential-<helper> binary named by credsStore/credHelpers in
+	// the operator's ~/.docker/config.json.
+	EngineAuthTypeDockercfgHelper = "dockercfg-helper"
+
+	// EngineAuthTypeDockerConfig reads a standard ~/.docker/config.json
+	// directly, resolving any credsStore/credHelpers entries it contains.
+	EngineAuthTypeDockerConfig = "docker-config"
+
+	// defaultDockerConfigPath is where the Docker CLI keeps its config file
+	// absent $DOCKER_CONFIG.
+	defaultDockerConfigPath = ".docker/config.json"
+
+	// defaultEngineAuthRegistry is used when ECS_ENGINE_AUTH_REGISTRY is not
+	// set; it matches the Docker CLI's own default registry.
+	defaultEngineAuthRegistry = "https://index.docker.io/v1/"
+
+	// credentialCacheTTL bounds how long a resolved credential is reused
+	// before the helper is invoked again, so that short-lived credentials
+	// (e.g. from docker-credential-ecr-login) get refreshed.
+	credentialCacheTTL = 10 * time.Minute
+)
+
+// dockerConfigFile is the subset of ~/.docker/config.json the agent cares
+// about: which credential helper backs which registry, and any
+// statically stored (non-helper-backed) credentials.
+type dockerConfigFile struct {
+	CredsStore  string                           `json:"credsStore"`
+	CredHelpers map[string]string                `json:"credHelpers"`
+	Auths       map[string]dockerConfigAuthEntry `json:"auths"`
+}
+
+// dockerConfigAuthEntry is one entry of config.json's "auths" map: a
+// base64-encoded "user:pass" pair, as written by `docker login` when no
+// credential helper is configured for the registry.
+type dockerConfigAuthEntry struct {
+	Auth string `json:"auth"`
+}
+
+// credHelperOutput is the JSON a docker-credential-<helper> binary writes to
+// stdout in response to a "get" request.
+type credHelperOutput struct {
+	ServerURL string `json:"ServerURL"`
+	Username  string `json:"Username"`
+	Secret    string `json:"Secret"`
+}
+
+// dockerAuthEntry is one entry of the engine auth JSON blob the docker
+// client expects, keyed by registry hostname.
+type dockerAuthEntry struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+var (
+	credentialCacheMu sync.Mutex
+	credentialCache   = map[string]cachedCredential{}
+)
+
+type cachedCredential struct {
+	authData []byte
+	expiry   time.Time
+}
+
+// resolveEngineAuthData returns the engine auth JSON blob for authType,
+// resolving it via a Docker credential helper, or (for
+// EngineAuthTypeDockerConfig) a statically stored config.json credential,
+// when authType calls for one. For any other authType it is a no-op; the
+// caller should fall back to ECS_ENGINE_AUTH_DATA.
+func resolveEngineAuthData(authType, registry string) ([]byte, error) {
+	if authType != EngineAuthTypeDockercfgHelper && authType != EngineAuthTypeDockerConfig {
+		return nil, nil
+	}
+
+	credentialCacheMu.Lock()
+	if cached, ok := credentialCache[registry]; ok && time.Now().Before(cached.expiry) {
+		credentialCacheMu.Unlock()
+		return cached.authData, nil
+	}
+	credentialCacheMu.Unlock()
+
+	username, secret, err := resolveCredential(authType, registry)
+	if err != nil {
+		return nil, err
+	}
+
+	authData, err := json.Marshal(map[string]dockerAuthEntry{
+		registry: {Username: username, Password: secret},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	credentialCacheMu.Lock()
+	credentialCache[registry] = cachedCredential{authData: authData, expiry: time.Now().Add(credentialCacheTTL)}
+	credentialCacheMu.Unlock()
+
+	return authData, nil
+}
+
+// resolveCredential resolves registry's credential for authType: always
+// via a credential helper for EngineAuthTypeDockercfgHelper, or -- for
+// EngineAuthTypeDockerConfig -- a credential helper if one applies,
+// falling back to a statically stored credential in config.json's "auths"
+// map (the form `docker login` writes when no helper is configured).
+func resolveCredential(authType, registry string) (username, secret string, err error) {
+	cf, path, err := readDockerConfigFile()
+	if err != nil {
+		return "", "", err
+	}
+
+	if helper := credentialHelperFor(cf, registry); helper != "" {
+		return invokeCredentialHelper(helper, registry)
+	}
+
+	if authType == EngineAuthTypeDockerConfig {
+		return dockerConfigAuthFor(cf, registry, path)
+	}
+
+	return "", "", fmt.Errorf("no credential helper configured for registry %q in %q", registry, path)
+}
+
+// readDockerConfigFile reads and parses the Docker CLI's config.json.
+func readDockerConfigFile() (dockerConfigFile, string, error) {
+	path := dockerConfigPath()
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return dockerConfigFile{}, path, fmt.Errorf("unable to read docker config %q: %v", path, err)
+	}
+
+	var cf dockerConfigFile
+	if err := json.Unmarshal(data, &cf); err != nil {
+		return dockerConfigFile{}, path, fmt.Errorf("unable to parse docker config %q: %v", path, err)
+	}
+
+	return cf, path, nil
+}
+
+// credentialHelperFor returns the docker-credential-<helper> name that
+// backs registry, per config.json's credHelpers map, falling back to the
+// global credsStore. It returns "" if no helper applies to registry.
+func credentialHelperFor(cf dockerConfigFile, registry string) string {
+	if helper, ok := cf.CredHelpers[registry]; ok && helper != "" {
+		return helper
+	}
+	return cf.CredsStore
+}
+
+// dockerConfigAuthFor reads registry's statically stored credential from
+// config.json's "auths" map.
+func dockerConfigAuthFor(cf dockerConfigFile, registry, path string) (username, secret string, err error) {
+	entry, ok := cf.Auths[registry]
+	if !ok || entry.Auth == "" {
+		return "", "", fmt.Errorf("no stored credential for registry %q in %q", registry, path)
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(entry.Auth)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid auth entry for registry %q in %q: %v", registry, path, err)
+	}
+
+	userPass := strings.SplitN(string(decoded), ":", 2)
+	if len(userPass) != 2 {
+		return "", "", fmt.Errorf("invalid auth entry for registry %q in %q", registry, path)
+	}
+	return userPass[0], userPass[1], nil
+}
+
+// invokeCredentialHelper runs docker-credential-<helper> get, speaking the
+// same stdin/stdout protocol as the Docker CLI.
+func invokeCredentialHelper(helper, registry string) (username, secret string, err error) {
+	cmd := exec.Command("docker-credential-"+helper, "get")
+	cmd.Stdin = strings.NewReader(registry)
+
+	var stdout bytes.Buffer
+	cmd.Stdout = &stdout
+
+	if err := cmd.Run(); err != nil {
+		return "", "", fmt.Errorf("docker-credential-%s get failed: %v", helper, err)
+	}
+
+	var out credHelperOutput
+	if err := json.Unmarshal(stdout.Bytes(), &out); err != nil {
+		return "", "", fmt.Errorf("docker-credential-%s returned invalid JSON: %v", helper, err)
+	}
+
+	return out.Username, out.Secret, nil
+}
+
+// dockerConfigPath returns the path to the Docker CLI's config.json,
+// honoring $DOCKER_CONFIG the same way the Docker CLI does.
+func dockerConfigPath() string {
+	if dir := os.Getenv("DOCKER_CONFIG"); dir != "" {
+		return filepath.Join(dir, "config.json")
+	}
+	home := os.Getenv("HOME")
+	if home == "" {
+		home = os.Getenv("USERPROFILE")
+	}
+	return filepath.Join(home, defaultDockerConfigPath)
+}
@@ -0,0 +1,87 @@
+// Copyright 2014-2015 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package config
+
+import (
+	"encoding/base64"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeDockerConfig(t *testing.T, contents string) string {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "docker-config")
+	if err != nil {
+		t.Fatalf("TempDir: %v", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	if err := ioutil.WriteFile(filepath.Join(dir, "config.json"), []byte(contents), 0600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	old := os.Getenv("DOCKER_CONFIG")
+	os.Setenv("DOCKER_CONFIG", dir)
+	t.Cleanup(func() { os.Setenv("DOCKER_CONFIG", old) })
+
+	return dir
+}
+
+func TestResolveCredentialDockerConfigStaticAuth(t *testing.T) {
+	auth := base64.StdEncoding.EncodeToString([]byte("someuser:somepass"))
+	writeDockerConfig(t, `{"auths":{"https://my-private-registry.example.com":{"auth":"`+auth+`"}}}`)
+
+	username, secret, err := resolveCredential(EngineAuthTypeDockerConfig, "https://my-private-registry.example.com")
+	if err != nil {
+		t.Fatalf("resolveCredential returned err: %v", err)
+	}
+	if username != "someuser" || secret != "somepass" {
+		t.Fatalf("resolveCredential = (%q, %q), want (someuser, somepass)", username, secret)
+	}
+}
+
+func TestResolveCredentialDockercfgHelperIgnoresStaticAuth(t *testing.T) {
+	// dockercfg-helper always requires a helper; it must not fall back to
+	// a statically stored "auths" entry the way docker-config does.
+	auth := base64.StdEncoding.EncodeToString([]byte("someuser:somepass"))
+	writeDockerConfig(t, `{"auths":{"https://my-private-registry.example.com":{"auth":"`+auth+`"}}}`)
+
+	if _, _, err := resolveCredential(EngineAuthTypeDockercfgHelper, "https://my-private-registry.example.com"); err == nil {
+		t.Fatal("resolveCredential(dockercfg-helper) = nil error, want error")
+	}
+}
+
+func TestResolveCredentialNoMatchingEntry(t *testing.T) {
+	writeDockerConfig(t, `{"auths":{"https://other-registry.example.com":{"auth":"x"}}}`)
+
+	if _, _, err := resolveCredential(EngineAuthTypeDockerConfig, "https://my-private-registry.example.com"); err == nil {
+		t.Fatal("resolveCredential = nil error, want error for registry with no entry")
+	}
+}
+
+func TestCredentialHelperForPrefersCredHelpersOverCredsStore(t *testing.T) {
+	cf := dockerConfigFile{
+		CredsStore:  "desktop",
+		CredHelpers: map[string]string{"https://my-registry.example.com": "ecr-login"},
+	}
+
+	if got := credentialHelperFor(cf, "https://my-registry.example.com"); got != "ecr-login" {
+		t.Fatalf("credentialHelperFor = %q, want ecr-login", got)
+	}
+	if got := credentialHelperFor(cf, "https://index.docker.io/v1/"); got != "desktop" {
+		t.Fatalf("credentialHelperFor = %q, want desktop (credsStore fallback)", got)
+	}
+}
@@ -0,0 +1,37 @@
+// Copyright 2014-2015 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// +build !windows
+
+package config
+
+import (
+	"context"
+	"fmt"
+	"net"
+)
+
+// defaultDockerEndpoint is the Docker remote API endpoint used on this
+// platform when DOCKER_HOST is not set.
+const defaultDockerEndpoint = "unix:///var/run/docker.sock"
+
+// platformDockerEndpointSchemes lists the DockerEndpoint URL schemes this
+// platform knows how to dial.
+var platformDockerEndpointSchemes = []string{"unix", "tcp", "http", "https"}
+
+// dialNamedPipe never succeeds on this platform: "npipe" endpoints are
+// already rejected by ParseDockerEndpoint's platformDockerEndpointSchemes
+// check, so DialDockerEndpoint never reaches here in practice.
+func dialNamedPipe(ctx context.Context, pipePath string) (net.Conn, error) {
+	return nil, fmt.Errorf("named pipe endpoints are not supported on this platform: %q", pipePath)
+}
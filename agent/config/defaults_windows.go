@@ -0,0 +1,45 @@
+// Copyright 2014-2015 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// +build windows
+
+package config
+
+import (
+	"context"
+	"net"
+	"time"
+
+	winio "github.com/Microsoft/go-winio"
+)
+
+// defaultDockerEndpoint is the Docker remote API endpoint used on this
+// platform when DOCKER_HOST is not set. Windows reaches the daemon over a
+// named pipe rather than a unix socket.
+const defaultDockerEndpoint = "npipe:////./pipe/docker_engine"
+
+// platformDockerEndpointSchemes lists the DockerEndpoint URL schemes this
+// platform knows how to dial.
+var platformDockerEndpointSchemes = []string{"npipe", "tcp", "http", "https"}
+
+// dialNamedPipe dials a Windows named pipe Docker endpoint using go-winio,
+// the same library the Docker CLI itself uses to talk to npipe endpoints --
+// net.Dial has no notion of a named pipe.
+func dialNamedPipe(ctx context.Context, pipePath string) (net.Conn, error) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return winio.DialPipe(pipePath, nil)
+	}
+	timeout := time.Until(deadline)
+	return winio.DialPipe(pipePath, &timeout)
+}
@@ -0,0 +1,94 @@
+// Copyright 2014-2015 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package config
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+)
+
+// dockerEndpointSchemes are the URL schemes the agent understands at all,
+// independent of platform. A given platform may only support a subset of
+// these; see platformDockerEndpointSchemes.
+var dockerEndpointSchemes = []string{"unix", "tcp", "npipe", "http", "https"}
+
+// ParseDockerEndpoint validates a DockerEndpoint value and returns its
+// scheme. It rejects schemes the agent has never heard of as well as
+// schemes this platform has no dialer for (e.g. "npipe" on Linux, "unix" on
+// Windows), so a misconfigured endpoint is caught at startup rather than
+// hanging on the first connect attempt.
+func ParseDockerEndpoint(endpoint string) (string, error) {
+	parsed, err := url.Parse(endpoint)
+	if err != nil {
+		return "", fmt.Errorf("invalid DockerEndpoint %q: %v", endpoint, err)
+	}
+
+	scheme := parsed.Scheme
+	if scheme == "" {
+		return "", fmt.Errorf("DockerEndpoint %q has no scheme", endpoint)
+	}
+
+	if !stringInSlice(scheme, dockerEndpointSchemes) {
+		return "", fmt.Errorf("DockerEndpoint %q has unsupported scheme %q", endpoint, scheme)
+	}
+	if !stringInSlice(scheme, platformDockerEndpointSchemes) {
+		return "", fmt.Errorf("DockerEndpoint %q uses scheme %q, which is not supported on this platform", endpoint, scheme)
+	}
+
+	return scheme, nil
+}
+
+// DialDockerEndpoint returns a dial function for endpoint, suitable for
+// plugging into the Docker client wrapper's http.Transport. For schemes
+// net.Dial already understands (unix, tcp) it's a thin wrapper; for
+// "npipe" endpoints, where net.Dial has no notion of a Windows named
+// pipe, it defers to the platform's go-winio-style pipe dialer so the
+// wrapper doesn't need to know the difference.
+func DialDockerEndpoint(endpoint string) (func(ctx context.Context, network, addr string) (net.Conn, error), error) {
+	scheme, err := ParseDockerEndpoint(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	if scheme == "npipe" {
+		pipePath := npipePath(endpoint)
+		return func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return dialNamedPipe(ctx, pipePath)
+		}, nil
+	}
+
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		var d net.Dialer
+		return d.DialContext(ctx, network, addr)
+	}, nil
+}
+
+// npipePath strips the "npipe://" prefix from a DockerEndpoint, leaving the
+// forward-slash pipe path that is handed to go-winio's DialPipe, e.g.
+// "npipe:////./pipe/docker_engine" becomes "//./pipe/docker_engine".
+func npipePath(endpoint string) string {
+	return strings.TrimPrefix(endpoint, "npipe://")
+}
+
+func stringInSlice(needle string, haystack []string) bool {
+	for _, s := range haystack {
+		if strings.EqualFold(s, needle) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,88 @@
+// Copyright 2014-2015 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package config
+
+import (
+	"context"
+	"testing"
+)
+
+func TestParseDockerEndpoint(t *testing.T) {
+	cases := []struct {
+		name     string
+		endpoint string
+		wantErr  bool
+	}{
+		{name: "unix", endpoint: "unix:///var/run/docker.sock", wantErr: !stringInSlice("unix", platformDockerEndpointSchemes)},
+		{name: "tcp", endpoint: "tcp://127.0.0.1:2375"},
+		{name: "no scheme", endpoint: "/var/run/docker.sock", wantErr: true},
+		{name: "unknown scheme", endpoint: "ftp://example.com", wantErr: true},
+		{name: "invalid url", endpoint: "://bad", wantErr: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := ParseDockerEndpoint(c.endpoint)
+			if c.wantErr && err == nil {
+				t.Fatalf("ParseDockerEndpoint(%q) = nil error, want error", c.endpoint)
+			}
+			if !c.wantErr && err != nil {
+				t.Fatalf("ParseDockerEndpoint(%q) = %v, want no error", c.endpoint, err)
+			}
+		})
+	}
+}
+
+func TestDialDockerEndpointRejectsUnsupportedScheme(t *testing.T) {
+	// "npipe" is not in platformDockerEndpointSchemes on this platform
+	// (see defaults_unix.go / defaults_windows.go), so DialDockerEndpoint
+	// must fail during ParseDockerEndpoint rather than handing back a
+	// dialer that can never connect.
+	if stringInSlice("npipe", platformDockerEndpointSchemes) {
+		t.Skip("npipe is supported on this platform")
+	}
+
+	_, err := DialDockerEndpoint("npipe:////./pipe/docker_engine")
+	if err == nil {
+		t.Fatal("DialDockerEndpoint(npipe) = nil error, want error")
+	}
+}
+
+func TestDialDockerEndpointUnix(t *testing.T) {
+	if !stringInSlice("unix", platformDockerEndpointSchemes) {
+		t.Skip("unix is not supported on this platform")
+	}
+
+	dial, err := DialDockerEndpoint("unix:///var/run/docker.sock")
+	if err != nil {
+		t.Fatalf("DialDockerEndpoint returned err: %v", err)
+	}
+	if dial == nil {
+		t.Fatal("DialDockerEndpoint returned a nil dialer")
+	}
+
+	// Dialing an almost certainly-absent socket should fail, not panic; it
+	// exercises the net.Dialer path rather than the npipe one.
+	if _, err := dial(context.Background(), "unix", "/var/run/docker-test-endpoint-does-not-exist.sock"); err == nil {
+		t.Fatal("dial to a nonexistent socket unexpectedly succeeded")
+	}
+}
+
+func TestNpipePath(t *testing.T) {
+	got := npipePath("npipe:////./pipe/docker_engine")
+	want := "//./pipe/docker_engine"
+	if got != want {
+		t.Fatalf("npipePath = %q, want %q", got, want)
+	}
+}
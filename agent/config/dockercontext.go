@@ -0,0 +1,137 @@
+// Copyright 2014-2015 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// dockerCLIConfigFile is the subset of ~/.docker/config.json the agent
+// cares about for context resolution.
+type dockerCLIConfigFile struct {
+	CurrentContext string `json:"currentContext"`
+}
+
+// dockerContextMetadata mirrors the "meta.json" the Docker CLI writes for
+// each context it manages.
+type dockerContextMetadata struct {
+	Name      string `json:"Name"`
+	Endpoints map[string]struct {
+		Host          string `json:"Host"`
+		SkipTLSVerify bool   `json:"SkipTLSVerify"`
+	} `json:"Endpoints"`
+}
+
+// DockerContextConfig resolves the Docker endpoint (and TLS material, if
+// any) from the Docker CLI's context store -- the same mechanism behind
+// `docker context use` -- so operators who already manage multiple engines
+// can point the agent at one by name instead of setting DOCKER_HOST. It
+// returns a zero Config if no non-default context is selected, or if the
+// context's files can't be read.
+func DockerContextConfig() Config {
+	configDir := dockerConfigDir()
+	ctxName := currentDockerContext(configDir)
+	if ctxName == "" || ctxName == "default" {
+		return Config{}
+	}
+
+	digest := dockerContextDigest(ctxName)
+	metaPath := filepath.Join(configDir, "contexts", "meta", digest, "meta.json")
+	data, err := ioutil.ReadFile(metaPath)
+	if err != nil {
+		log.Warn("Unable to read Docker context metadata", "context", ctxName, "err", err)
+		return Config{}
+	}
+
+	var meta dockerContextMetadata
+	if err := json.Unmarshal(data, &meta); err != nil {
+		log.Warn("Unable to parse Docker context metadata", "context", ctxName, "err", err)
+		return Config{}
+	}
+
+	dockerEndpoint, ok := meta.Endpoints["docker"]
+	if !ok || dockerEndpoint.Host == "" {
+		log.Warn("Docker context has no docker endpoint", "context", ctxName)
+		return Config{}
+	}
+
+	cfg := Config{
+		DockerEndpoint:      dockerEndpoint.Host,
+		DockerContext:       ctxName,
+		DockerSkipTLSVerify: dockerEndpoint.SkipTLSVerify,
+	}
+
+	tlsDir := filepath.Join(configDir, "contexts", "tls", digest, "docker")
+	if path := filepath.Join(tlsDir, "ca.pem"); fileExists(path) {
+		cfg.DockerCAPath = path
+	}
+	if path := filepath.Join(tlsDir, "cert.pem"); fileExists(path) {
+		cfg.DockerCertPath = path
+	}
+	if path := filepath.Join(tlsDir, "key.pem"); fileExists(path) {
+		cfg.DockerKeyPath = path
+	}
+
+	return cfg
+}
+
+// currentDockerContext returns the Docker CLI context to use: DOCKER_CONTEXT
+// if set, otherwise the config file's "currentContext", otherwise "default".
+func currentDockerContext(configDir string) string {
+	if ctx := os.Getenv("DOCKER_CONTEXT"); ctx != "" {
+		return ctx
+	}
+
+	data, err := ioutil.ReadFile(filepath.Join(configDir, "config.json"))
+	if err != nil {
+		return "default"
+	}
+
+	var cf dockerCLIConfigFile
+	if err := json.Unmarshal(data, &cf); err != nil || cf.CurrentContext == "" {
+		return "default"
+	}
+	return cf.CurrentContext
+}
+
+// dockerConfigDir returns $DOCKER_CONFIG, or ~/.docker if unset, matching
+// the Docker CLI's own resolution.
+func dockerConfigDir() string {
+	if dir := os.Getenv("DOCKER_CONFIG"); dir != "" {
+		return dir
+	}
+	home := os.Getenv("HOME")
+	if home == "" {
+		home = os.Getenv("USERPROFILE")
+	}
+	return filepath.Join(home, ".docker")
+}
+
+// dockerContextDigest is the hex-encoded SHA-256 digest of a context's
+// name, which is how the Docker CLI names that context's directory under
+// contexts/meta and contexts/tls.
+func dockerContextDigest(name string) string {
+	sum := sha256.Sum256([]byte(name))
+	return hex.EncodeToString(sum[:])
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,198 @@
+// Copyright 2014-2015 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestDockerContextDigest(t *testing.T) {
+	// Known SHA-256 hex digests, independent of this package's
+	// implementation, so the test catches a change to the hash algorithm
+	// or encoding rather than just mirroring dockerContextDigest's own
+	// logic back at it.
+	cases := []struct {
+		name string
+		want string
+	}{
+		{name: "my-context", want: "60b9683c6c2b05b8adc06ff4d150b15a5c69d74c7a7ee35bd733df12861dd2b0"},
+		{name: "default", want: "37a8eec1ce19687d132fe29051dca629d164e2c4958ba141d5f4133a33f0688f"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := dockerContextDigest(c.name); got != c.want {
+				t.Fatalf("dockerContextDigest(%q) = %q, want %q", c.name, got, c.want)
+			}
+		})
+	}
+}
+
+func TestCurrentDockerContext(t *testing.T) {
+	t.Run("DOCKER_CONTEXT env wins over config.json", func(t *testing.T) {
+		dir := t.TempDir()
+		writeFile(t, filepath.Join(dir, "config.json"), `{"currentContext":"from-config-file"}`)
+		t.Setenv("DOCKER_CONTEXT", "from-env")
+
+		if got := currentDockerContext(dir); got != "from-env" {
+			t.Fatalf("currentDockerContext = %q, want from-env", got)
+		}
+	})
+
+	t.Run("falls back to config.json currentContext", func(t *testing.T) {
+		dir := t.TempDir()
+		writeFile(t, filepath.Join(dir, "config.json"), `{"currentContext":"from-config-file"}`)
+		t.Setenv("DOCKER_CONTEXT", "")
+
+		if got := currentDockerContext(dir); got != "from-config-file" {
+			t.Fatalf("currentDockerContext = %q, want from-config-file", got)
+		}
+	})
+
+	t.Run("defaults to default when nothing is set", func(t *testing.T) {
+		dir := t.TempDir()
+		t.Setenv("DOCKER_CONTEXT", "")
+
+		if got := currentDockerContext(dir); got != "default" {
+			t.Fatalf("currentDockerContext = %q, want default", got)
+		}
+	})
+
+	t.Run("defaults to default on unparseable config.json", func(t *testing.T) {
+		dir := t.TempDir()
+		writeFile(t, filepath.Join(dir, "config.json"), `not json`)
+		t.Setenv("DOCKER_CONTEXT", "")
+
+		if got := currentDockerContext(dir); got != "default" {
+			t.Fatalf("currentDockerContext = %q, want default", got)
+		}
+	})
+}
+
+func TestDockerContextConfig(t *testing.T) {
+	t.Run("no context selected returns zero Config", func(t *testing.T) {
+		dir := t.TempDir()
+		t.Setenv("DOCKER_CONFIG", dir)
+		t.Setenv("DOCKER_CONTEXT", "")
+
+		if got := DockerContextConfig(); !reflect.DeepEqual(got, Config{}) {
+			t.Fatalf("DockerContextConfig() = %+v, want zero Config", got)
+		}
+	})
+
+	t.Run("resolves endpoint and TLS material for a named context", func(t *testing.T) {
+		dir := t.TempDir()
+		t.Setenv("DOCKER_CONFIG", dir)
+		t.Setenv("DOCKER_CONTEXT", "my-context")
+
+		digest := dockerContextDigest("my-context")
+		metaDir := filepath.Join(dir, "contexts", "meta", digest)
+		tlsDir := filepath.Join(dir, "contexts", "tls", digest, "docker")
+		mustMkdirAll(t, metaDir)
+		mustMkdirAll(t, tlsDir)
+
+		writeFile(t, filepath.Join(metaDir, "meta.json"), `{
+			"Name": "my-context",
+			"Endpoints": {
+				"docker": {"Host": "tcp://192.0.2.10:2376", "SkipTLSVerify": false}
+			}
+		}`)
+		writeFile(t, filepath.Join(tlsDir, "ca.pem"), "ca")
+		writeFile(t, filepath.Join(tlsDir, "cert.pem"), "cert")
+		writeFile(t, filepath.Join(tlsDir, "key.pem"), "key")
+
+		got := DockerContextConfig()
+
+		if got.DockerEndpoint != "tcp://192.0.2.10:2376" {
+			t.Errorf("DockerEndpoint = %q, want tcp://192.0.2.10:2376", got.DockerEndpoint)
+		}
+		if got.DockerContext != "my-context" {
+			t.Errorf("DockerContext = %q, want my-context", got.DockerContext)
+		}
+		if got.DockerSkipTLSVerify {
+			t.Errorf("DockerSkipTLSVerify = true, want false")
+		}
+		if got.DockerCAPath != filepath.Join(tlsDir, "ca.pem") {
+			t.Errorf("DockerCAPath = %q, want %q", got.DockerCAPath, filepath.Join(tlsDir, "ca.pem"))
+		}
+		if got.DockerCertPath != filepath.Join(tlsDir, "cert.pem") {
+			t.Errorf("DockerCertPath = %q, want %q", got.DockerCertPath, filepath.Join(tlsDir, "cert.pem"))
+		}
+		if got.DockerKeyPath != filepath.Join(tlsDir, "key.pem") {
+			t.Errorf("DockerKeyPath = %q, want %q", got.DockerKeyPath, filepath.Join(tlsDir, "key.pem"))
+		}
+	})
+
+	t.Run("missing TLS material is left blank, not an error", func(t *testing.T) {
+		dir := t.TempDir()
+		t.Setenv("DOCKER_CONFIG", dir)
+		t.Setenv("DOCKER_CONTEXT", "my-context")
+
+		digest := dockerContextDigest("my-context")
+		metaDir := filepath.Join(dir, "contexts", "meta", digest)
+		mustMkdirAll(t, metaDir)
+		writeFile(t, filepath.Join(metaDir, "meta.json"), `{
+			"Name": "my-context",
+			"Endpoints": {"docker": {"Host": "unix:///var/run/docker.sock"}}
+		}`)
+
+		got := DockerContextConfig()
+		if got.DockerCAPath != "" || got.DockerCertPath != "" || got.DockerKeyPath != "" {
+			t.Fatalf("expected blank TLS paths, got %+v", got)
+		}
+	})
+
+	t.Run("context with no docker endpoint returns zero Config", func(t *testing.T) {
+		dir := t.TempDir()
+		t.Setenv("DOCKER_CONFIG", dir)
+		t.Setenv("DOCKER_CONTEXT", "my-context")
+
+		digest := dockerContextDigest("my-context")
+		metaDir := filepath.Join(dir, "contexts", "meta", digest)
+		mustMkdirAll(t, metaDir)
+		writeFile(t, filepath.Join(metaDir, "meta.json"), `{"Name": "my-context", "Endpoints": {}}`)
+
+		if got := DockerContextConfig(); !reflect.DeepEqual(got, Config{}) {
+			t.Fatalf("DockerContextConfig() = %+v, want zero Config", got)
+		}
+	})
+
+	t.Run("missing meta.json returns zero Config", func(t *testing.T) {
+		dir := t.TempDir()
+		t.Setenv("DOCKER_CONFIG", dir)
+		t.Setenv("DOCKER_CONTEXT", "never-created")
+
+		if got := DockerContextConfig(); !reflect.DeepEqual(got, Config{}) {
+			t.Fatalf("DockerContextConfig() = %+v, want zero Config", got)
+		}
+	})
+}
+
+func writeFile(t *testing.T, path, contents string) {
+	t.Helper()
+	if err := ioutil.WriteFile(path, []byte(contents), 0600); err != nil {
+		t.Fatalf("WriteFile(%q): %v", path, err)
+	}
+}
+
+func mustMkdirAll(t *testing.T, path string) {
+	t.Helper()
+	if err := os.MkdirAll(path, 0700); err != nil {
+		t.Fatalf("MkdirAll(%q): %v", path, err)
+	}
+}
@@ -0,0 +1,118 @@
+// Copyright 2014-2015 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/aws/amazon-ecs-agent/agent/utils"
+)
+
+// loadFromEnv walks cfg's fields via reflection and populates any that carry
+// an `env:"ECS_FOO"` tag from the corresponding environment variable,
+// decoding according to the field's `type` tag (see decodeEnvValue). A
+// blank or unset variable leaves the field untouched, except Checkpoint,
+// which is always set (see below).
+func loadFromEnv(cfg *Config) {
+	cfgElem := reflect.ValueOf(cfg).Elem()
+	cfgType := cfgElem.Type()
+
+	for i := 0; i < cfgElem.NumField(); i++ {
+		field := cfgElem.Field(i)
+		structField := cfgType.Field(i)
+
+		envKey := structField.Tag.Get("env")
+		if envKey == "" {
+			continue
+		}
+		if structField.Name == "Checkpoint" {
+			// Checkpoint's default depends on DataDir; handled below.
+			continue
+		}
+
+		raw := os.Getenv(envKey)
+		if raw == "" {
+			continue
+		}
+
+		if err := decodeEnvValue(field, structField.Tag.Get("type"), raw); err != nil {
+			log.Warn("Invalid value for environment variable", "key", envKey, "err", err)
+		}
+	}
+
+	// Checkpoint's default depends on whether a data directory was
+	// configured, so it can't be expressed as a plain bool tag: default it
+	// on if ECS_DATADIR is set, off otherwise, for backwards compatibility.
+	cfg.Checkpoint = utils.ParseBool(os.Getenv("ECS_CHECKPOINT"), cfg.DataDir != "")
+}
+
+// decodeEnvValue decodes raw into field according to typeHint. An empty
+// typeHint means "plain string". Supported hints: bool, uint16, ports
+// (a JSON array of uint16, e.g. [1,2,3]), bytes, json (any JSON value
+// matching the field's type).
+func decodeEnvValue(field reflect.Value, typeHint, raw string) error {
+	switch typeHint {
+	case "", "string":
+		field.SetString(raw)
+	case "bool":
+		field.SetBool(utils.ParseBool(raw, false))
+	case "uint16":
+		v, err := strconv.ParseUint(raw, 10, 16)
+		if err != nil {
+			return fmt.Errorf("expected unsigned integer: %v", err)
+		}
+		field.SetUint(v)
+	case "ports":
+		var ports []uint16
+		if err := json.Unmarshal([]byte(raw), &ports); err != nil {
+			return fmt.Errorf("expected a JSON array like [1,2,3]: %v", err)
+		}
+		field.Set(reflect.ValueOf(ports))
+	case "bytes":
+		field.SetBytes([]byte(raw))
+	case "json":
+		target := reflect.New(field.Type())
+		if err := json.Unmarshal([]byte(raw), target.Interface()); err != nil {
+			return fmt.Errorf("invalid JSON: %v", err)
+		}
+		field.Set(target.Elem())
+	default:
+		return fmt.Errorf("unknown type hint %q", typeHint)
+	}
+	return nil
+}
+
+// DumpSchema returns a human readable list of every environment variable
+// named by an `env` tag on Config, and the Config field each one populates.
+// Variables read elsewhere (e.g. DOCKER_CONFIG, DOCKER_CONTEXT) are not
+// listed. It's a
+// convenience for operators; the agent itself never calls it.
+func DumpSchema() string {
+	var lines []string
+	t := reflect.TypeOf(Config{})
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		envKey := f.Tag.Get("env")
+		if envKey == "" {
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("%s (%s, %s)", envKey, f.Name, f.Type))
+	}
+	return strings.Join(lines, "\n")
+}
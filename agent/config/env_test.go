@@ -0,0 +1,262 @@
+// Copyright 2014-2015 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDecodeEnvValueString(t *testing.T) {
+	cases := []struct {
+		name     string
+		typeHint string
+		raw      string
+	}{
+		{name: "empty type hint", typeHint: "", raw: "my-cluster"},
+		{name: "explicit string hint", typeHint: "string", raw: "my-cluster"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var s string
+			field := reflect.ValueOf(&s).Elem()
+			if err := decodeEnvValue(field, c.typeHint, c.raw); err != nil {
+				t.Fatalf("decodeEnvValue returned err: %v", err)
+			}
+			if s != c.raw {
+				t.Fatalf("decoded %q, want %q", s, c.raw)
+			}
+		})
+	}
+}
+
+func TestDecodeEnvValueBool(t *testing.T) {
+	cases := []struct {
+		raw     string
+		want    bool
+		wantErr bool
+	}{
+		{raw: "true", want: true},
+		{raw: "1", want: true},
+		{raw: "false", want: false},
+		{raw: "garbage", want: false}, // ParseBool falls back to its default on unrecognized input
+	}
+
+	for _, c := range cases {
+		t.Run(c.raw, func(t *testing.T) {
+			var b bool
+			field := reflect.ValueOf(&b).Elem()
+			err := decodeEnvValue(field, "bool", c.raw)
+			if c.wantErr {
+				if err == nil {
+					t.Fatal("decodeEnvValue = nil error, want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("decodeEnvValue returned err: %v", err)
+			}
+			if b != c.want {
+				t.Fatalf("decoded %v, want %v", b, c.want)
+			}
+		})
+	}
+}
+
+func TestDecodeEnvValueUint16(t *testing.T) {
+	cases := []struct {
+		name    string
+		raw     string
+		want    uint16
+		wantErr bool
+	}{
+		{name: "valid", raw: "1024", want: 1024},
+		{name: "zero", raw: "0", want: 0},
+		{name: "not a number", raw: "abc", wantErr: true},
+		{name: "overflows uint16", raw: "99999", wantErr: true},
+		{name: "negative", raw: "-1", wantErr: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var v uint16
+			field := reflect.ValueOf(&v).Elem()
+			err := decodeEnvValue(field, "uint16", c.raw)
+			if c.wantErr {
+				if err == nil {
+					t.Fatal("decodeEnvValue = nil error, want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("decodeEnvValue returned err: %v", err)
+			}
+			if v != c.want {
+				t.Fatalf("decoded %d, want %d", v, c.want)
+			}
+		})
+	}
+}
+
+func TestDecodeEnvValuePorts(t *testing.T) {
+	cases := []struct {
+		name    string
+		raw     string
+		want    []uint16
+		wantErr bool
+	}{
+		{name: "valid array", raw: "[1,2,3]", want: []uint16{1, 2, 3}},
+		{name: "empty array", raw: "[]", want: []uint16{}},
+		{name: "not json", raw: "1,2,3", wantErr: true},
+		{name: "wrong shape", raw: `{"a":1}`, wantErr: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var ports []uint16
+			field := reflect.ValueOf(&ports).Elem()
+			err := decodeEnvValue(field, "ports", c.raw)
+			if c.wantErr {
+				if err == nil {
+					t.Fatal("decodeEnvValue = nil error, want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("decodeEnvValue returned err: %v", err)
+			}
+			if !reflect.DeepEqual(ports, c.want) {
+				t.Fatalf("decoded %v, want %v", ports, c.want)
+			}
+		})
+	}
+}
+
+func TestDecodeEnvValueBytes(t *testing.T) {
+	var b []byte
+	field := reflect.ValueOf(&b).Elem()
+	if err := decodeEnvValue(field, "bytes", "some-auth-blob"); err != nil {
+		t.Fatalf("decodeEnvValue returned err: %v", err)
+	}
+	if string(b) != "some-auth-blob" {
+		t.Fatalf("decoded %q, want %q", b, "some-auth-blob")
+	}
+}
+
+func TestDecodeEnvValueJSON(t *testing.T) {
+	cases := []struct {
+		name    string
+		raw     string
+		want    []string
+		wantErr bool
+	}{
+		{name: "valid array", raw: `["json-file","syslog"]`, want: []string{"json-file", "syslog"}},
+		{name: "invalid json", raw: `[`, wantErr: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var drivers []string
+			field := reflect.ValueOf(&drivers).Elem()
+			err := decodeEnvValue(field, "json", c.raw)
+			if c.wantErr {
+				if err == nil {
+					t.Fatal("decodeEnvValue = nil error, want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("decodeEnvValue returned err: %v", err)
+			}
+			if !reflect.DeepEqual(drivers, c.want) {
+				t.Fatalf("decoded %v, want %v", drivers, c.want)
+			}
+		})
+	}
+}
+
+func TestDecodeEnvValueUnknownTypeHint(t *testing.T) {
+	var s string
+	field := reflect.ValueOf(&s).Elem()
+	if err := decodeEnvValue(field, "not-a-real-hint", "x"); err == nil {
+		t.Fatal("decodeEnvValue = nil error, want error for unknown type hint")
+	}
+}
+
+func TestLoadFromEnvCheckpointDefaultsOnDataDir(t *testing.T) {
+	cases := []struct {
+		name       string
+		dataDir    string
+		checkpoint string
+		want       bool
+	}{
+		{name: "no datadir, no explicit checkpoint -> off", dataDir: "", checkpoint: "", want: false},
+		{name: "datadir set, no explicit checkpoint -> on", dataDir: "/data/", checkpoint: "", want: true},
+		{name: "datadir set, explicit checkpoint off", dataDir: "/data/", checkpoint: "false", want: false},
+		{name: "no datadir, explicit checkpoint on", dataDir: "", checkpoint: "true", want: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			t.Setenv("ECS_DATADIR", c.dataDir)
+			t.Setenv("ECS_CHECKPOINT", c.checkpoint)
+
+			cfg := Config{}
+			loadFromEnv(&cfg)
+
+			if cfg.Checkpoint != c.want {
+				t.Fatalf("Checkpoint = %v, want %v", cfg.Checkpoint, c.want)
+			}
+		})
+	}
+}
+
+func TestLoadFromEnvPopulatesTaggedFields(t *testing.T) {
+	t.Setenv("ECS_CLUSTER", "my-cluster")
+	t.Setenv("ECS_RESERVED_MEMORY", "256")
+	t.Setenv("ECS_RESERVED_PORTS", "[22,2375]")
+	t.Setenv("ECS_DISABLE_METRICS", "true")
+	t.Setenv("ECS_AVAILABLE_LOGGING_DRIVERS", `["json-file","syslog"]`)
+
+	cfg := Config{}
+	loadFromEnv(&cfg)
+
+	if cfg.Cluster != "my-cluster" {
+		t.Errorf("Cluster = %q, want my-cluster", cfg.Cluster)
+	}
+	if cfg.ReservedMemory != 256 {
+		t.Errorf("ReservedMemory = %d, want 256", cfg.ReservedMemory)
+	}
+	if !reflect.DeepEqual(cfg.ReservedPorts, []uint16{22, 2375}) {
+		t.Errorf("ReservedPorts = %v, want [22 2375]", cfg.ReservedPorts)
+	}
+	if !cfg.DisableMetrics {
+		t.Errorf("DisableMetrics = false, want true")
+	}
+	if !reflect.DeepEqual(cfg.AvailableLoggingDrivers, []string{"json-file", "syslog"}) {
+		t.Errorf("AvailableLoggingDrivers = %v, want [json-file syslog]", cfg.AvailableLoggingDrivers)
+	}
+}
+
+func TestLoadFromEnvLeavesFieldUnsetOnBlankEnv(t *testing.T) {
+	t.Setenv("ECS_CLUSTER", "")
+
+	cfg := Config{Cluster: "already-set"}
+	loadFromEnv(&cfg)
+
+	if cfg.Cluster != "already-set" {
+		t.Fatalf("Cluster = %q, want already-set (blank env must not overwrite)", cfg.Cluster)
+	}
+}
@@ -0,0 +1,112 @@
+// Copyright 2014-2015 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// LogConfig is the logging driver configuration the task engine applies to
+// a container when it does not request its own. Driver/Options mirror
+// Docker's own --log-driver/--log-opt; AvailableOnHost is the whitelist of
+// drivers the daemon actually supports.
+type LogConfig struct {
+	Driver          string
+	Options         map[string]string
+	AvailableOnHost []string
+}
+
+// LogConfig assembles the host's default LogConfig from EngineLogDriver,
+// EngineLogOpts, and AvailableLoggingDrivers.
+func (cfg *Config) LogConfig() (LogConfig, error) {
+	opts, err := ParseLogOpts(cfg.EngineLogOpts)
+	if err != nil {
+		return LogConfig{}, err
+	}
+	return LogConfig{
+		Driver:          cfg.EngineLogDriver,
+		Options:         opts,
+		AvailableOnHost: cfg.AvailableLoggingDrivers,
+	}, nil
+}
+
+// ParseLogOpts parses DOCKER_LOG_OPTS, which may be either a comma
+// separated list of key=value pairs or a JSON object.
+func ParseLogOpts(raw string) (map[string]string, error) {
+	opts := make(map[string]string)
+
+	trimmed := strings.TrimSpace(raw)
+	if trimmed == "" {
+		return opts, nil
+	}
+
+	if strings.HasPrefix(trimmed, "{") {
+		if err := json.Unmarshal([]byte(trimmed), &opts); err != nil {
+			return nil, fmt.Errorf("invalid JSON for DOCKER_LOG_OPTS: %v", err)
+		}
+		return opts, nil
+	}
+
+	for _, pair := range strings.Split(trimmed, ",") {
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("invalid DOCKER_LOG_OPTS entry %q, expected key=value", pair)
+		}
+		opts[strings.TrimSpace(kv[0])] = strings.TrimSpace(kv[1])
+	}
+	return opts, nil
+}
+
+// ContainerLogConfig resolves the LogConfig to apply for a single
+// container, given the driver/options its task definition requested. An
+// empty requestedDriver means "use the host default" and requestedOpts is
+// then ignored; a non-empty requestedDriver is used with requestedOpts as
+// given, without the host default options. This is the hook
+// the task engine calls while translating a container definition into a
+// Docker HostConfig: a per-task override is rejected here, via
+// ValidateLogDriver, if its driver isn't whitelisted, rather than failing
+// later inside the Docker daemon.
+func (cfg *Config) ContainerLogConfig(requestedDriver string, requestedOpts map[string]string) (LogConfig, error) {
+	if requestedDriver == "" {
+		return cfg.LogConfig()
+	}
+
+	if err := cfg.ValidateLogDriver(requestedDriver); err != nil {
+		return LogConfig{}, err
+	}
+
+	return LogConfig{
+		Driver:          requestedDriver,
+		Options:         requestedOpts,
+		AvailableOnHost: cfg.AvailableLoggingDrivers,
+	}, nil
+}
+
+// ValidateLogDriver returns an error if requested is not in
+// AvailableLoggingDrivers. An empty whitelist allows any driver, and an
+// empty requested driver is always allowed (it means "use the host
+// default").
+func (cfg *Config) ValidateLogDriver(requested string) error {
+	if requested == "" || len(cfg.AvailableLoggingDrivers) == 0 {
+		return nil
+	}
+	for _, driver := range cfg.AvailableLoggingDrivers {
+		if driver == requested {
+			return nil
+		}
+	}
+	return fmt.Errorf("logging driver %q is not in the whitelisted set of available logging drivers: %v", requested, cfg.AvailableLoggingDrivers)
+}
@@ -0,0 +1,81 @@
+// Copyright 2014-2015 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package config
+
+import "testing"
+
+func TestValidateLogDriver(t *testing.T) {
+	cases := []struct {
+		name      string
+		available []string
+		requested string
+		wantErr   bool
+	}{
+		{name: "empty whitelist allows anything", available: nil, requested: "json-file"},
+		{name: "empty requested always allowed", available: []string{"syslog"}, requested: ""},
+		{name: "whitelisted driver", available: []string{"json-file", "syslog"}, requested: "syslog"},
+		{name: "non-whitelisted driver", available: []string{"json-file"}, requested: "syslog", wantErr: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			cfg := &Config{AvailableLoggingDrivers: c.available}
+			err := cfg.ValidateLogDriver(c.requested)
+			if c.wantErr && err == nil {
+				t.Fatalf("ValidateLogDriver(%q) = nil error, want error", c.requested)
+			}
+			if !c.wantErr && err != nil {
+				t.Fatalf("ValidateLogDriver(%q) = %v, want no error", c.requested, err)
+			}
+		})
+	}
+}
+
+func TestContainerLogConfigUsesHostDefaultWhenNotOverridden(t *testing.T) {
+	cfg := &Config{EngineLogDriver: "json-file", EngineLogOpts: "max-size=10m"}
+
+	got, err := cfg.ContainerLogConfig("", nil)
+	if err != nil {
+		t.Fatalf("ContainerLogConfig returned err: %v", err)
+	}
+	if got.Driver != "json-file" || got.Options["max-size"] != "10m" {
+		t.Fatalf("ContainerLogConfig = %+v, want host default", got)
+	}
+}
+
+func TestContainerLogConfigRejectsNonWhitelistedOverride(t *testing.T) {
+	cfg := &Config{
+		EngineLogDriver:         "json-file",
+		AvailableLoggingDrivers: []string{"json-file"},
+	}
+
+	if _, err := cfg.ContainerLogConfig("syslog", nil); err == nil {
+		t.Fatal("ContainerLogConfig(syslog) = nil error, want error (not whitelisted)")
+	}
+}
+
+func TestContainerLogConfigAllowsWhitelistedOverride(t *testing.T) {
+	cfg := &Config{
+		EngineLogDriver:         "json-file",
+		AvailableLoggingDrivers: []string{"json-file", "syslog"},
+	}
+
+	got, err := cfg.ContainerLogConfig("syslog", map[string]string{"syslog-address": "udp://1.2.3.4:514"})
+	if err != nil {
+		t.Fatalf("ContainerLogConfig returned err: %v", err)
+	}
+	if got.Driver != "syslog" || got.Options["syslog-address"] != "udp://1.2.3.4:514" {
+		t.Fatalf("ContainerLogConfig = %+v, want the per-task override", got)
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright 2014-2015 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package config
+
+// Config is the top level configuration for the Agent. Fields are tagged so
+// that the reflection-based helpers in config.go (Merge, Complete,
+// CheckMissingAndDepreciated, TrimWhitespace) and loadFromEnv (env.go) know
+// how to treat them. The `env` tag names the environment variable a field
+// is read from; `type` hints how to decode it when it isn't a plain string
+// (see env.go for the supported set). A field with no `env` tag is never
+// populated from the environment.
+type Config struct {
+	// Cluster is the cluster this agent should check into
+	Cluster string `env:"ECS_CLUSTER" trim:"true"`
+
+	// APIEndpoint is the endpoint, if any, to give the cluster manager
+	APIEndpoint string `env:"ECS_BACKEND_HOST"`
+
+	// AWSRegion is the region to run in
+	AWSRegion string `env:"AWS_DEFAULT_REGION" missing:"fatal" trim:"true"`
+
+	// DockerEndpoint is the address the agent dials to reach the Docker
+	// remote API. It defaults to the platform's usual endpoint; see
+	// defaults_unix.go / defaults_windows.go.
+	DockerEndpoint string `env:"DOCKER_HOST"`
+
+	ReservedPorts    []uint16 `env:"ECS_RESERVED_PORTS" type:"ports"`
+	ReservedPortsUDP []uint16 `env:"ECS_RESERVED_PORTS_UDP" type:"ports"`
+
+	// DataDir also governs Checkpoint's default, which loadFromEnv handles
+	// specially; see env.go.
+	DataDir string `env:"ECS_DATADIR"`
+
+	Checkpoint bool `env:"ECS_CHECKPOINT" type:"bool"`
+
+	EngineAuthType string `env:"ECS_ENGINE_AUTH_TYPE"`
+	EngineAuthData []byte `env:"ECS_ENGINE_AUTH_DATA" type:"bytes"`
+
+	// EngineAuthRegistry is the registry credentials are resolved for when
+	// EngineAuthType is "dockercfg-helper" or "docker-config". Defaults to
+	// Docker Hub.
+	EngineAuthRegistry string `env:"ECS_ENGINE_AUTH_REGISTRY"`
+
+	UpdatesEnabled    bool   `env:"ECS_UPDATES_ENABLED" type:"bool"`
+	UpdateDownloadDir string `env:"ECS_UPDATE_DOWNLOAD_DIR"`
+
+	DisableMetrics  bool   `env:"ECS_DISABLE_METRICS" type:"bool"`
+	DockerGraphPath string `env:"ECS_DOCKER_GRAPHPATH"`
+
+	ReservedMemory uint16 `env:"ECS_RESERVED_MEMORY" type:"uint16"`
+
+	// EngineLogDriver and EngineLogOpts hold the host default logging
+	// configuration read from DOCKER_LOG_DRIVER / DOCKER_LOG_OPTS.
+	EngineLogDriver string `env:"DOCKER_LOG_DRIVER"`
+	EngineLogOpts   string `env:"DOCKER_LOG_OPTS"`
+
+	// AvailableLoggingDrivers whitelists the logging drivers the host's
+	// Docker daemon actually has, read from ECS_AVAILABLE_LOGGING_DRIVERS.
+	// EngineLogDriver, and any per-task override, must be one of these. An
+	// empty list disables the whitelist check.
+	AvailableLoggingDrivers []string `env:"ECS_AVAILABLE_LOGGING_DRIVERS" type:"json"`
+
+	// ClusterArn is a deprecated alias for Cluster, kept for compatibility.
+	ClusterArn string `deprecated:"Use Cluster instead"`
+
+	// DockerContext is the name of the Docker CLI context DockerEndpoint
+	// (and the TLS fields below) were resolved from, if any. Populated by
+	// DockerContextConfig; empty when the agent is using DOCKER_HOST or the
+	// platform default instead. These fields have no `env` tag and no
+	// entry in DefaultConfig, so they are tagged `complete:"skip"`: a host
+	// that isn't using a named Docker context would otherwise never see
+	// them become non-zero, which would permanently defeat Complete()'s
+	// "no file / network IO needed" fast path in NewConfig.
+	DockerContext string `complete:"skip"`
+
+	DockerCAPath        string `complete:"skip"`
+	DockerCertPath      string `complete:"skip"`
+	DockerKeyPath       string `complete:"skip"`
+	DockerSkipTLSVerify bool   `complete:"skip"`
+}